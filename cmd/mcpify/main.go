@@ -0,0 +1,65 @@
+// Command mcpify loads and validates the REST-to-MCP bridge config and
+// reports how many APIs it declares, or, with -import-openapi, imports an
+// upstream API from an OpenAPI 3 document instead of requiring the operator
+// to hand-write its endpoint stanzas.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/fumiya007/mcpify/internal/config"
+)
+
+func main() {
+	configPath := flag.String("config", "", "path to the bridge config file (defaults to ~/.config/mcp-bridge/config.json)")
+	importOpenAPI := flag.String("import-openapi", "", "path to an OpenAPI 3 document to import as a new API config")
+	baseURL := flag.String("base-url", "", "base URL for the imported API, overriding any server URL the document declares")
+	out := flag.String("out", "", "where to write the imported API config as JSON (defaults to stdout)")
+	flag.Parse()
+
+	if *importOpenAPI != "" {
+		if err := runImportOpenAPI(*importOpenAPI, *baseURL, *out); err != nil {
+			log.Fatalf("mcpify: %v", err)
+		}
+		return
+	}
+
+	cfg, err := config.LoadConfig(*configPath)
+	if err != nil {
+		log.Fatalf("mcpify: loading config: %v", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		log.Fatalf("mcpify: invalid config: %v", err)
+	}
+
+	log.Printf("mcpify: loaded %d API(s) for server %q", len(cfg.APIs), cfg.Server.Name)
+}
+
+// runImportOpenAPI derives an APIConfig from specPath and writes it as JSON
+// to out (or stdout if out is empty), so operators can redirect it straight
+// into a config file or a conf.d fragment instead of hand-writing endpoints.
+func runImportOpenAPI(specPath, baseURL, out string) error {
+	api, err := config.LoadFromOpenAPI(specPath)
+	if err != nil {
+		return fmt.Errorf("importing OpenAPI document: %w", err)
+	}
+
+	if baseURL != "" {
+		api.BaseURL = baseURL
+	}
+
+	data, err := json.MarshalIndent(api, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling imported API config: %w", err)
+	}
+
+	if out == "" {
+		_, err = os.Stdout.Write(append(data, '\n'))
+		return err
+	}
+	return os.WriteFile(out, append(data, '\n'), 0644)
+}
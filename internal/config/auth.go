@@ -0,0 +1,246 @@
+package config
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// NewHTTPClient builds an *http.Client for api that attaches every
+// configured Auth entry to each outgoing request and, if an mtls scheme is
+// present, presents the configured client certificate. This is the client
+// a dispatcher should use to talk to api so tool calls automatically carry
+// its credentials without threading them through by hand.
+func NewHTTPClient(api *APIConfig) (*http.Client, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	for _, auth := range api.Auth {
+		if auth.Type != "mtls" || auth.MTLS == nil {
+			continue
+		}
+		tlsConfig, err := auth.MTLS.TLSConfig()
+		if err != nil {
+			return nil, fmt.Errorf("API %s: %w", api.Name, err)
+		}
+		transport.TLSClientConfig = tlsConfig
+	}
+
+	client := &http.Client{Transport: &authRoundTripper{base: transport, auth: api.Auth}}
+	if api.Timeout > 0 {
+		client.Timeout = time.Duration(api.Timeout) * time.Second
+	}
+	return client, nil
+}
+
+// authRoundTripper applies an APIConfig's Auth entries to every outgoing
+// request before delegating to base.
+type authRoundTripper struct {
+	base http.RoundTripper
+	auth []AuthConfig
+}
+
+func (rt *authRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	for _, auth := range rt.auth {
+		if err := auth.Apply(req); err != nil {
+			return nil, fmt.Errorf("applying %s auth: %w", auth.Type, err)
+		}
+	}
+	return rt.base.RoundTrip(req)
+}
+
+// Apply attaches the credentials described by a to req: basic auth via
+// http.Request.SetBasicAuth, bearer and oauth2 tokens as an Authorization
+// header, and API keys in a header or the query string. mtls is a no-op
+// here since it is negotiated by the transport.
+func (a *AuthConfig) Apply(req *http.Request) error {
+	switch a.Type {
+	case "basic":
+		if a.Basic != nil {
+			req.SetBasicAuth(a.Basic.Username, a.Basic.Password)
+		}
+	case "bearer":
+		if a.Bearer != nil {
+			req.Header.Set("Authorization", "Bearer "+a.Bearer.Token)
+		}
+	case "apiKey":
+		if a.APIKey != nil {
+			applyAPIKey(req, a.APIKey)
+		}
+	case "oauth2":
+		if a.OAuth2 != nil {
+			token, err := defaultOAuth2Cache.Get(a.OAuth2)
+			if err != nil {
+				return fmt.Errorf("oauth2 token fetch: %w", err)
+			}
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+	case "mtls":
+		// mTLS is negotiated at the transport level; see MTLS.TLSConfig.
+	}
+	return nil
+}
+
+func applyAPIKey(req *http.Request, key *APIKeyAuthConfig) {
+	switch key.In {
+	case "query":
+		q := req.URL.Query()
+		q.Set(key.Name, key.Key)
+		req.URL.RawQuery = q.Encode()
+	default:
+		req.Header.Set(key.Name, key.Key)
+	}
+}
+
+// TLSConfig builds a *tls.Config presenting the configured client
+// certificate, optionally pinned to a custom CA bundle.
+func (m *MTLSAuthConfig) TLSConfig() (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(m.CertFile, m.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("loading client certificate: %w", err)
+	}
+
+	tlsConfig := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+	}
+
+	if m.CAFile != "" {
+		caPEM, err := os.ReadFile(m.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading CA bundle: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caPEM) {
+			return nil, fmt.Errorf("no certificates found in CA bundle %s", m.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	return tlsConfig, nil
+}
+
+// oauth2TokenCache caches client-credentials tokens per TokenURL+ClientID so
+// concurrent tool calls against the same API share one token. Refresh is
+// lazy: Get only re-fetches once a token has expired, on the first caller
+// to notice, and that caller's fetch is shared with any other callers that
+// land on the same key while it's in flight rather than each re-fetching.
+type oauth2TokenCache struct {
+	mu       sync.Mutex
+	tokens   map[string]cachedToken
+	inflight map[string]*oauth2Fetch
+	client   *http.Client
+}
+
+type cachedToken struct {
+	value     string
+	expiresAt time.Time
+}
+
+// oauth2Fetch represents a token fetch in progress for a given cache key;
+// callers that arrive while one is outstanding wait on done instead of
+// starting their own request.
+type oauth2Fetch struct {
+	done  chan struct{}
+	token cachedToken
+	err   error
+}
+
+var defaultOAuth2Cache = &oauth2TokenCache{
+	tokens:   make(map[string]cachedToken),
+	inflight: make(map[string]*oauth2Fetch),
+	client:   &http.Client{Timeout: 30 * time.Second},
+}
+
+func (c *oauth2TokenCache) Get(cfg *OAuth2ClientCredentialsConfig) (string, error) {
+	key := cfg.TokenURL + "|" + cfg.ClientID
+
+	c.mu.Lock()
+	if tok, ok := c.tokens[key]; ok && time.Now().Before(tok.expiresAt) {
+		c.mu.Unlock()
+		return tok.value, nil
+	}
+
+	if f, ok := c.inflight[key]; ok {
+		c.mu.Unlock()
+		<-f.done
+		if f.err != nil {
+			return "", f.err
+		}
+		return f.token.value, nil
+	}
+
+	f := &oauth2Fetch{done: make(chan struct{})}
+	c.inflight[key] = f
+	c.mu.Unlock()
+
+	f.token, f.err = c.fetch(cfg)
+
+	c.mu.Lock()
+	if f.err == nil {
+		c.tokens[key] = f.token
+	}
+	delete(c.inflight, key)
+	c.mu.Unlock()
+	close(f.done)
+
+	if f.err != nil {
+		return "", f.err
+	}
+	return f.token.value, nil
+}
+
+func (c *oauth2TokenCache) fetch(cfg *OAuth2ClientCredentialsConfig) (cachedToken, error) {
+	form := url.Values{}
+	form.Set("grant_type", "client_credentials")
+	form.Set("client_id", cfg.ClientID)
+	form.Set("client_secret", cfg.ClientSecret)
+	if len(cfg.Scopes) > 0 {
+		form.Set("scope", strings.Join(cfg.Scopes, " "))
+	}
+
+	req, err := http.NewRequest(http.MethodPost, cfg.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return cachedToken{}, fmt.Errorf("building token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return cachedToken{}, fmt.Errorf("requesting token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return cachedToken{}, fmt.Errorf("token endpoint returned status %d", resp.StatusCode)
+	}
+
+	var body struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return cachedToken{}, fmt.Errorf("decoding token response: %w", err)
+	}
+	if body.AccessToken == "" {
+		return cachedToken{}, fmt.Errorf("token response missing access_token")
+	}
+
+	expiresIn := body.ExpiresIn
+	if expiresIn <= 0 {
+		expiresIn = 60
+	}
+	// Refresh a bit early so an in-flight call never sees a token that
+	// expires mid-request.
+	refreshSkew := 10 * time.Second
+	return cachedToken{
+		value:     body.AccessToken,
+		expiresAt: time.Now().Add(time.Duration(expiresIn)*time.Second - refreshSkew),
+	}, nil
+}
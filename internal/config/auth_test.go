@@ -0,0 +1,205 @@
+package config
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestAuthConfigApply(t *testing.T) {
+	tests := []struct {
+		name  string
+		auth  AuthConfig
+		check func(t *testing.T, req *http.Request)
+	}{
+		{
+			name: "bearer",
+			auth: AuthConfig{Type: "bearer", Bearer: &BearerAuthConfig{Token: "tok"}},
+			check: func(t *testing.T, req *http.Request) {
+				if got := req.Header.Get("Authorization"); got != "Bearer tok" {
+					t.Errorf("Authorization = %q, want %q", got, "Bearer tok")
+				}
+			},
+		},
+		{
+			name: "apiKey in header",
+			auth: AuthConfig{Type: "apiKey", APIKey: &APIKeyAuthConfig{Name: "X-API-Key", Key: "secret", In: "header"}},
+			check: func(t *testing.T, req *http.Request) {
+				if got := req.Header.Get("X-API-Key"); got != "secret" {
+					t.Errorf("X-API-Key header = %q, want %q", got, "secret")
+				}
+			},
+		},
+		{
+			name: "apiKey in query",
+			auth: AuthConfig{Type: "apiKey", APIKey: &APIKeyAuthConfig{Name: "api_key", Key: "secret", In: "query"}},
+			check: func(t *testing.T, req *http.Request) {
+				if got := req.URL.Query().Get("api_key"); got != "secret" {
+					t.Errorf("api_key query param = %q, want %q", got, "secret")
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, "http://example.com/foo", nil)
+			if err != nil {
+				t.Fatalf("NewRequest: %v", err)
+			}
+			if err := tt.auth.Apply(req); err != nil {
+				t.Fatalf("Apply: %v", err)
+			}
+			tt.check(t, req)
+		})
+	}
+}
+
+// TestOAuth2TokenCacheCoalescesConcurrentFetches verifies that concurrent
+// Get calls that land while no valid token is cached share a single
+// upstream fetch instead of each independently requesting a new token.
+func TestOAuth2TokenCacheCoalescesConcurrentFetches(t *testing.T) {
+	var fetches int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&fetches, 1)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"access_token":"tok","expires_in":60}`))
+	}))
+	defer server.Close()
+
+	cache := &oauth2TokenCache{
+		tokens:   make(map[string]cachedToken),
+		inflight: make(map[string]*oauth2Fetch),
+		client:   server.Client(),
+	}
+	cfg := &OAuth2ClientCredentialsConfig{TokenURL: server.URL, ClientID: "client"}
+
+	var wg sync.WaitGroup
+	const callers = 10
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := cache.Get(cfg); err != nil {
+				t.Errorf("Get: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := atomic.LoadInt32(&fetches); got != 1 {
+		t.Errorf("upstream fetches = %d, want 1", got)
+	}
+}
+
+// TestNewHTTPClientAppliesAuth verifies that a client built by NewHTTPClient
+// attaches the API's configured auth to every request it sends, without the
+// caller having to call Apply itself.
+func TestNewHTTPClientAppliesAuth(t *testing.T) {
+	var gotAuth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+	}))
+	defer server.Close()
+
+	api := &APIConfig{
+		Name: "svc",
+		Auth: []AuthConfig{{Type: "bearer", Bearer: &BearerAuthConfig{Token: "tok"}}},
+	}
+
+	client, err := NewHTTPClient(api)
+	if err != nil {
+		t.Fatalf("NewHTTPClient: %v", err)
+	}
+
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	resp.Body.Close()
+
+	if gotAuth != "Bearer tok" {
+		t.Errorf("Authorization seen by server = %q, want %q", gotAuth, "Bearer tok")
+	}
+}
+
+// TestMTLSAuthConfigTLSConfig covers the two load paths for MTLS.TLSConfig:
+// presenting a client certificate, and pinning a custom CA bundle.
+func TestMTLSAuthConfigTLSConfig(t *testing.T) {
+	dir := t.TempDir()
+	certPEM, keyPEM := generateSelfSignedCert(t)
+
+	certFile := filepath.Join(dir, "client.crt")
+	keyFile := filepath.Join(dir, "client.key")
+	caFile := filepath.Join(dir, "ca.crt")
+	writeConfigFile(t, certFile, string(certPEM))
+	writeConfigFile(t, keyFile, string(keyPEM))
+	writeConfigFile(t, caFile, string(certPEM))
+
+	m := &MTLSAuthConfig{CertFile: certFile, KeyFile: keyFile}
+	tlsConfig, err := m.TLSConfig()
+	if err != nil {
+		t.Fatalf("TLSConfig: %v", err)
+	}
+	if len(tlsConfig.Certificates) != 1 {
+		t.Fatalf("Certificates = %d, want 1", len(tlsConfig.Certificates))
+	}
+	if tlsConfig.RootCAs != nil {
+		t.Error("RootCAs set without a CAFile, want nil")
+	}
+
+	m.CAFile = caFile
+	tlsConfig, err = m.TLSConfig()
+	if err != nil {
+		t.Fatalf("TLSConfig with CAFile: %v", err)
+	}
+	if tlsConfig.RootCAs == nil {
+		t.Error("RootCAs is nil, want the pinned CA pool")
+	}
+}
+
+func TestMTLSAuthConfigTLSConfigMissingCert(t *testing.T) {
+	m := &MTLSAuthConfig{CertFile: "/no/such/cert.pem", KeyFile: "/no/such/key.pem"}
+	if _, err := m.TLSConfig(); err == nil {
+		t.Fatal("TLSConfig with a missing cert file succeeded, want error")
+	}
+}
+
+// generateSelfSignedCert returns a freshly minted self-signed cert/key pair
+// PEM-encoded, for tests that need client certificate material on disk.
+func generateSelfSignedCert(t *testing.T) (certPEM, keyPEM []byte) {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generating key: %v", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "mcpify-test-client"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("creating certificate: %v", err)
+	}
+
+	certPEM = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM = pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	return certPEM, keyPEM
+}
@@ -5,67 +5,123 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
 )
 
+var pathParamPattern = regexp.MustCompile(`\{([^}]+)\}`)
+
 type Config struct {
-	APIs      []APIConfig       `json:"apis"`
-	Server    ServerConfig      `json:"server"`
-	Headers   map[string]string `json:"headers,omitempty"`
-	Transport TransportConfig   `json:"transport,omitempty"`
+	APIs      []APIConfig       `json:"apis" yaml:"apis"`
+	Server    ServerConfig      `json:"server" yaml:"server"`
+	Headers   map[string]string `json:"headers,omitempty" yaml:"headers,omitempty"`
+	Transport TransportConfig   `json:"transport,omitempty" yaml:"transport,omitempty"`
+
+	// Include lists glob patterns of additional config fragments (JSON or
+	// YAML) to merge into this one; see merge.go.
+	Include []string `json:"include,omitempty" yaml:"include,omitempty"`
+
+	// raw holds the config bytes exactly as loaded from disk or, once
+	// include fragments have been merged in, the merged config marshaled
+	// as JSON; either way it is taken before any
+	// ${env:...}/${file:...}/${cmd:...} secret references were expanded.
+	// SaveConfig writes this back verbatim so secrets are never persisted
+	// in resolved form.
+	raw []byte `json:"-" yaml:"-"`
 }
 
 type APIConfig struct {
-	Name      string           `json:"name"`
-	BaseURL   string           `json:"baseUrl"`
-	Timeout   int              `json:"timeout"`
-	Headers   map[string]string `json:"headers,omitempty"`
-	Auth      []AuthConfig     `json:"auth,omitempty"`
-	Endpoints []CustomEndpoint `json:"endpoints,omitempty"`
+	Name      string            `json:"name" yaml:"name"`
+	BaseURL   string            `json:"baseUrl" yaml:"baseUrl"`
+	Timeout   int               `json:"timeout" yaml:"timeout"`
+	Headers   map[string]string `json:"headers,omitempty" yaml:"headers,omitempty"`
+	Auth      []AuthConfig      `json:"auth,omitempty" yaml:"auth,omitempty"`
+	Endpoints []CustomEndpoint  `json:"endpoints,omitempty" yaml:"endpoints,omitempty"`
+	Policy    *Policy           `json:"policy,omitempty" yaml:"policy,omitempty"`
 }
 
 type AuthConfig struct {
-	Type  string           `json:"type"`
-	Basic *BasicAuthConfig `json:"basic,omitempty"`
+	Type   string                         `json:"type" yaml:"type"`
+	Basic  *BasicAuthConfig               `json:"basic,omitempty" yaml:"basic,omitempty"`
+	Bearer *BearerAuthConfig              `json:"bearer,omitempty" yaml:"bearer,omitempty"`
+	APIKey *APIKeyAuthConfig              `json:"apiKey,omitempty" yaml:"apiKey,omitempty"`
+	OAuth2 *OAuth2ClientCredentialsConfig `json:"oauth2,omitempty" yaml:"oauth2,omitempty"`
+	MTLS   *MTLSAuthConfig                `json:"mtls,omitempty" yaml:"mtls,omitempty"`
 }
 
 type BasicAuthConfig struct {
-	Username string `json:"username"`
-	Password string `json:"password"`
+	Username string `json:"username" yaml:"username"`
+	Password string `json:"password" yaml:"password"`
+}
+
+type BearerAuthConfig struct {
+	Token string `json:"token" yaml:"token"`
+}
+
+// APIKeyAuthConfig describes an API key credential attached either to a
+// request header or a query parameter.
+type APIKeyAuthConfig struct {
+	Key  string `json:"key" yaml:"key"`
+	Name string `json:"name" yaml:"name"`
+	In   string `json:"in" yaml:"in"`
+}
+
+// OAuth2ClientCredentialsConfig drives the OAuth2 client-credentials grant.
+// Tokens fetched from TokenURL are cached and lazily refreshed the next
+// time they're needed after expiry, coalescing concurrent callers onto one
+// fetch; see auth.go for the refresh implementation.
+type OAuth2ClientCredentialsConfig struct {
+	TokenURL     string   `json:"tokenUrl" yaml:"tokenUrl"`
+	ClientID     string   `json:"clientId" yaml:"clientId"`
+	ClientSecret string   `json:"clientSecret" yaml:"clientSecret"`
+	Scopes       []string `json:"scopes,omitempty" yaml:"scopes,omitempty"`
+}
+
+// MTLSAuthConfig points at PEM-encoded client certificate material used to
+// authenticate to the upstream API over mutual TLS.
+type MTLSAuthConfig struct {
+	CertFile string `json:"certFile" yaml:"certFile"`
+	KeyFile  string `json:"keyFile" yaml:"keyFile"`
+	CAFile   string `json:"caFile,omitempty" yaml:"caFile,omitempty"`
 }
 
 type ServerConfig struct {
-	Name        string `json:"name"`
-	Version     string `json:"version"`
-	Description string `json:"description"`
+	Name        string `json:"name" yaml:"name"`
+	Version     string `json:"version" yaml:"version"`
+	Description string `json:"description" yaml:"description"`
 }
 
 type TransportConfig struct {
-	Type string               `json:"type"`
-	HTTP *HTTPTransportConfig `json:"http,omitempty"`
+	Type string               `json:"type" yaml:"type"`
+	HTTP *HTTPTransportConfig `json:"http,omitempty" yaml:"http,omitempty"`
 }
 
 type HTTPTransportConfig struct {
-	Host string `json:"host"`
-	Port int    `json:"port"`
-	CORS bool   `json:"cors"`
+	Host string `json:"host" yaml:"host"`
+	Port int    `json:"port" yaml:"port"`
+	CORS bool   `json:"cors" yaml:"cors"`
 }
 
 type CustomEndpoint struct {
-	Name        string            `json:"name"`
-	Description string            `json:"description"`
-	Method      string            `json:"method"`
-	Path        string            `json:"path"`
-	Parameters  []CustomParameter `json:"parameters"`
-	Headers     map[string]string `json:"headers,omitempty"`
+	Name        string            `json:"name" yaml:"name"`
+	Description string            `json:"description" yaml:"description"`
+	Method      string            `json:"method" yaml:"method"`
+	Path        string            `json:"path" yaml:"path"`
+	Parameters  []CustomParameter `json:"parameters" yaml:"parameters"`
+	Headers     map[string]string `json:"headers,omitempty" yaml:"headers,omitempty"`
+	Response    *ResponseConfig   `json:"response,omitempty" yaml:"response,omitempty"`
+	// Policy overrides the owning APIConfig's Policy for this endpoint
+	// only. It is validated on its own; fields left unset here are not
+	// filled in from the API-level policy.
+	Policy *Policy `json:"policy,omitempty" yaml:"policy,omitempty"`
 }
 
 type CustomParameter struct {
-	Name        string      `json:"name"`
-	Type        string      `json:"type"`
-	Required    bool        `json:"required"`
-	Description string      `json:"description"`
-	Default     interface{} `json:"default,omitempty"`
-	In          string      `json:"in"`
+	Name        string      `json:"name" yaml:"name"`
+	Type        string      `json:"type" yaml:"type"`
+	Required    bool        `json:"required" yaml:"required"`
+	Description string      `json:"description" yaml:"description"`
+	Default     interface{} `json:"default,omitempty" yaml:"default,omitempty"`
+	In          string      `json:"in" yaml:"in"`
 }
 
 func LoadConfig(configPath string) (*Config, error) {
@@ -77,17 +133,21 @@ func LoadConfig(configPath string) (*Config, error) {
 		return getDefaultConfig(), nil
 	}
 
-	data, err := os.ReadFile(configPath)
+	config, err := loadConfigFile(configPath)
+	if err != nil {
+		return nil, err
+	}
+
+	config, err = applyIncludes(config, configPath)
 	if err != nil {
-		return nil, fmt.Errorf("error reading config file: %w", err)
+		return nil, fmt.Errorf("error applying config includes: %w", err)
 	}
 
-	var config Config
-	if err := json.Unmarshal(data, &config); err != nil {
-		return nil, fmt.Errorf("error parsing config file: %w", err)
+	if err := resolveSecrets(config); err != nil {
+		return nil, fmt.Errorf("error resolving secrets in config file: %w", err)
 	}
 
-	return &config, nil
+	return config, nil
 }
 
 func SaveConfig(config *Config, configPath string) error {
@@ -99,9 +159,17 @@ func SaveConfig(config *Config, configPath string) error {
 		return fmt.Errorf("error creating config directory: %w", err)
 	}
 
-	data, err := json.MarshalIndent(config, "", "  ")
-	if err != nil {
-		return fmt.Errorf("error marshaling config: %w", err)
+	// Prefer the bytes the config was loaded from so that secret
+	// references stay unresolved on disk; only marshal from the in-memory
+	// struct when there is no original source (e.g. a config built
+	// programmatically).
+	data := config.raw
+	if data == nil {
+		var err error
+		data, err = json.MarshalIndent(config, "", "  ")
+		if err != nil {
+			return fmt.Errorf("error marshaling config: %w", err)
+		}
 	}
 
 	if err := os.WriteFile(configPath, data, 0644); err != nil {
@@ -161,6 +229,10 @@ func (c *Config) Validate() error {
 			c.APIs[i].Timeout = 30
 		}
 
+		if err := c.APIs[i].Policy.validate(); err != nil {
+			return fmt.Errorf("API %s: policy: %w", api.Name, err)
+		}
+
 		// Validate authentication configuration
 		for j, auth := range api.Auth {
 			if auth.Type == "" {
@@ -178,6 +250,53 @@ func (c *Config) Validate() error {
 				if auth.Basic.Password == "" {
 					return fmt.Errorf("API %s: basic auth password is required (auth index %d)", api.Name, j)
 				}
+			case "bearer":
+				if auth.Bearer == nil {
+					return fmt.Errorf("API %s: bearer auth configuration is required when type is 'bearer' (auth index %d)", api.Name, j)
+				}
+				if auth.Bearer.Token == "" {
+					return fmt.Errorf("API %s: bearer auth token is required (auth index %d)", api.Name, j)
+				}
+			case "apiKey":
+				if auth.APIKey == nil {
+					return fmt.Errorf("API %s: apiKey auth configuration is required when type is 'apiKey' (auth index %d)", api.Name, j)
+				}
+				if auth.APIKey.Key == "" {
+					return fmt.Errorf("API %s: apiKey value is required (auth index %d)", api.Name, j)
+				}
+				if auth.APIKey.Name == "" {
+					return fmt.Errorf("API %s: apiKey name is required (auth index %d)", api.Name, j)
+				}
+				switch auth.APIKey.In {
+				case "":
+					api.Auth[j].APIKey.In = "header"
+				case "header", "query":
+				default:
+					return fmt.Errorf("API %s: apiKey 'in' must be 'header' or 'query' (auth index %d)", api.Name, j)
+				}
+			case "oauth2":
+				if auth.OAuth2 == nil {
+					return fmt.Errorf("API %s: oauth2 configuration is required when type is 'oauth2' (auth index %d)", api.Name, j)
+				}
+				if auth.OAuth2.TokenURL == "" {
+					return fmt.Errorf("API %s: oauth2 token URL is required (auth index %d)", api.Name, j)
+				}
+				if auth.OAuth2.ClientID == "" {
+					return fmt.Errorf("API %s: oauth2 client ID is required (auth index %d)", api.Name, j)
+				}
+				if auth.OAuth2.ClientSecret == "" {
+					return fmt.Errorf("API %s: oauth2 client secret is required (auth index %d)", api.Name, j)
+				}
+			case "mtls":
+				if auth.MTLS == nil {
+					return fmt.Errorf("API %s: mTLS configuration is required when type is 'mtls' (auth index %d)", api.Name, j)
+				}
+				if auth.MTLS.CertFile == "" {
+					return fmt.Errorf("API %s: mTLS cert file is required (auth index %d)", api.Name, j)
+				}
+				if auth.MTLS.KeyFile == "" {
+					return fmt.Errorf("API %s: mTLS key file is required (auth index %d)", api.Name, j)
+				}
 			default:
 				return fmt.Errorf("API %s: unsupported auth type '%s' (auth index %d)", api.Name, auth.Type, j)
 			}
@@ -209,6 +328,32 @@ func (c *Config) Validate() error {
 					api.Endpoints[j].Parameters[k].Type = "string"
 				}
 			}
+
+			for _, match := range pathParamPattern.FindAllStringSubmatch(endpoint.Path, -1) {
+				name := match[1]
+				found := false
+				for _, param := range endpoint.Parameters {
+					if param.Name == name && (param.In == "path" || param.In == "") {
+						found = true
+						break
+					}
+				}
+				if !found {
+					return fmt.Errorf("API %s, endpoint %s: path parameter %q has no matching parameter definition", api.Name, endpoint.Name, name)
+				}
+			}
+
+			if endpoint.Response != nil {
+				if err := api.Endpoints[j].Response.validate(); err != nil {
+					return fmt.Errorf("API %s, endpoint %s: %w", api.Name, endpoint.Name, err)
+				}
+			}
+
+			if endpoint.Policy != nil {
+				if err := api.Endpoints[j].Policy.validate(); err != nil {
+					return fmt.Errorf("API %s, endpoint %s: policy: %w", api.Name, endpoint.Name, err)
+				}
+			}
 		}
 	}
 
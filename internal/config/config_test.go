@@ -0,0 +1,170 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestSaveConfigRoundTripsUnresolvedSecretsAcrossIncludes guards against a
+// regression where merging include fragments discarded the loaded config's
+// raw bytes, so SaveConfig fell back to marshaling the in-memory struct —
+// which by then had resolveSecrets' expansions baked in, and so would have
+// written the plaintext secret to disk instead of the original reference.
+func TestSaveConfigRoundTripsUnresolvedSecretsAcrossIncludes(t *testing.T) {
+	t.Setenv("MCPIFY_TEST_ROUNDTRIP_SECRET", "super-secret-value")
+
+	dir := t.TempDir()
+	basePath := filepath.Join(dir, "base.json")
+	writeConfigFile(t, basePath, `{
+		"include": ["conf.d/*.json"],
+		"apis": [{"name": "svc", "baseUrl": "http://base"}]
+	}`)
+
+	mustMkdir(t, filepath.Join(dir, "conf.d"))
+	writeConfigFile(t, filepath.Join(dir, "conf.d", "svc.json"), `{
+		"apis": [{
+			"name": "svc",
+			"baseUrl": "http://overridden",
+			"auth": [{"type": "basic", "basic": {"username": "u", "password": "${env:MCPIFY_TEST_ROUNDTRIP_SECRET}"}}]
+		}]
+	}`)
+
+	cfg, err := LoadConfig(basePath)
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+
+	if got := cfg.APIs[0].Auth[0].Basic.Password; got != "super-secret-value" {
+		t.Fatalf("in-memory password = %q, want resolved value", got)
+	}
+
+	outPath := filepath.Join(dir, "out.json")
+	if err := SaveConfig(cfg, outPath); err != nil {
+		t.Fatalf("SaveConfig: %v", err)
+	}
+
+	saved, err := os.ReadFile(outPath)
+	if err != nil {
+		t.Fatalf("reading saved config: %v", err)
+	}
+
+	if strings.Contains(string(saved), "super-secret-value") {
+		t.Fatalf("saved config contains the resolved secret, want the unresolved reference: %s", saved)
+	}
+	if !strings.Contains(string(saved), "${env:MCPIFY_TEST_ROUNDTRIP_SECRET}") {
+		t.Fatalf("saved config does not contain the unresolved secret reference: %s", saved)
+	}
+}
+
+// TestConfigValidateAcceptsFullyPopulatedConfig exercises Validate end to
+// end against a config using bearer auth, API- and endpoint-level
+// policies, and response shaping together, rather than each in isolation.
+func TestConfigValidateAcceptsFullyPopulatedConfig(t *testing.T) {
+	cfg := &Config{
+		APIs: []APIConfig{
+			{
+				Name:    "svc",
+				BaseURL: "http://example.com",
+				Auth: []AuthConfig{
+					{Type: "bearer", Bearer: &BearerAuthConfig{Token: "tok"}},
+				},
+				Policy: &Policy{RateLimit: &RateLimitPolicy{RPS: 5, Burst: 1}},
+				Endpoints: []CustomEndpoint{
+					{
+						Name:   "get-widget",
+						Method: "GET",
+						Path:   "/widgets/{id}",
+						Parameters: []CustomParameter{
+							{Name: "id", In: "path"},
+						},
+						Response: &ResponseConfig{
+							Extract: ".data",
+							Rename:  map[string]string{"id": "widget_id"},
+						},
+						Policy: &Policy{Retry: &RetryPolicy{MaxAttempts: 3}},
+					},
+				},
+			},
+		},
+	}
+
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+
+	if cfg.APIs[0].Endpoints[0].Parameters[0].Type != "string" {
+		t.Errorf("path parameter Type not defaulted, got %q", cfg.APIs[0].Endpoints[0].Parameters[0].Type)
+	}
+	if cfg.APIs[0].Policy.RateLimit.limiter == nil {
+		t.Fatal("Policy.validate did not run for the API-level policy")
+	}
+	if cfg.APIs[0].Endpoints[0].Policy.Retry.backoff == 0 {
+		t.Fatal("Policy.validate did not run for the endpoint-level policy")
+	}
+	if _, err := cfg.APIs[0].Endpoints[0].Response.Query(); err != nil {
+		t.Fatalf("Response.validate did not compile Extract: %v", err)
+	}
+}
+
+// TestConfigValidateRejectsInvalidConfigs checks that Validate rejects
+// each of several distinct ways a config can be malformed, one per case.
+func TestConfigValidateRejectsInvalidConfigs(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  *Config
+	}{
+		{
+			name: "no APIs",
+			cfg:  &Config{},
+		},
+		{
+			name: "missing base URL",
+			cfg:  &Config{APIs: []APIConfig{{Name: "svc"}}},
+		},
+		{
+			name: "oauth2 auth missing client secret",
+			cfg: &Config{APIs: []APIConfig{{
+				Name: "svc", BaseURL: "http://example.com",
+				Auth: []AuthConfig{{Type: "oauth2", OAuth2: &OAuth2ClientCredentialsConfig{
+					TokenURL: "http://example.com/token", ClientID: "id",
+				}}},
+			}}},
+		},
+		{
+			name: "unknown path parameter",
+			cfg: &Config{APIs: []APIConfig{{
+				Name: "svc", BaseURL: "http://example.com",
+				Endpoints: []CustomEndpoint{{
+					Name: "get-widget", Method: "GET", Path: "/widgets/{id}",
+				}},
+			}}},
+		},
+		{
+			name: "invalid response extract expression",
+			cfg: &Config{APIs: []APIConfig{{
+				Name: "svc", BaseURL: "http://example.com",
+				Endpoints: []CustomEndpoint{{
+					Name: "get-widget", Method: "GET", Path: "/widgets",
+					Response: &ResponseConfig{Extract: "not ( valid jq"},
+				}},
+			}}},
+		},
+		{
+			name: "invalid policy circuit breaker",
+			cfg: &Config{APIs: []APIConfig{{
+				Name: "svc", BaseURL: "http://example.com",
+				Policy: &Policy{CircuitBreaker: &CircuitBreakerPolicy{FailureThreshold: 0}},
+			}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.cfg.Validate(); err == nil {
+				t.Fatal("Validate succeeded, want error")
+			}
+		})
+	}
+}
@@ -0,0 +1,189 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+)
+
+// applyIncludes resolves base.Include as glob patterns relative to the
+// directory of basePath, loads each matched fragment, and merges them into
+// base in file order, later files overriding earlier ones. Array fields
+// keyed by name (APIs, Endpoints) are merged by name rather than replaced
+// wholesale, so a fragment can patch a single endpoint of an API declared
+// elsewhere. This is what lets a deployment split dozens of upstream APIs
+// across one file per API, e.g. under conf.d/*.yaml.
+func applyIncludes(base *Config, basePath string) (*Config, error) {
+	visited := map[string]bool{}
+	if abs, err := filepath.Abs(basePath); err == nil {
+		visited[abs] = true
+	}
+	return applyIncludesVisited(base, basePath, visited)
+}
+
+// applyIncludesVisited is applyIncludes with the set of absolute paths
+// already resolved in this chain, so that two fragments including each
+// other fail with an error instead of recursing forever.
+func applyIncludesVisited(base *Config, basePath string, visited map[string]bool) (*Config, error) {
+	if len(base.Include) == 0 {
+		return base, nil
+	}
+
+	dir := filepath.Dir(basePath)
+
+	for _, pattern := range base.Include {
+		if !filepath.IsAbs(pattern) {
+			pattern = filepath.Join(dir, pattern)
+		}
+
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid include pattern %q: %w", pattern, err)
+		}
+
+		for _, match := range matches {
+			abs, err := filepath.Abs(match)
+			if err != nil {
+				return nil, fmt.Errorf("resolving include %q: %w", match, err)
+			}
+			if visited[abs] {
+				return nil, fmt.Errorf("include cycle detected: %q is already included in this chain", match)
+			}
+			visited[abs] = true
+
+			fragment, err := loadConfigFile(match)
+			if err != nil {
+				return nil, err
+			}
+
+			fragment, err = applyIncludesVisited(fragment, match, visited)
+			if err != nil {
+				return nil, err
+			}
+
+			mergeConfig(base, fragment)
+		}
+	}
+
+	// A merged config is no longer a 1:1 representation of any single
+	// file on disk, so there are no original bytes for SaveConfig to
+	// round-trip. Snapshot the merge result here, before LoadConfig runs
+	// resolveSecrets on it, so SaveConfig still writes back unresolved
+	// ${env:...}/${file:...}/${cmd:...} references rather than secrets
+	// resolveSecrets expanded in place.
+	data, err := json.MarshalIndent(base, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("marshaling merged config: %w", err)
+	}
+	base.raw = data
+
+	return base, nil
+}
+
+// mergeConfig merges src into dst in place. Scalar fields in src override
+// dst's when non-zero; maps are merged key by key; APIs and Endpoints are
+// merged by name so a fragment can add or override a single entry without
+// repeating its siblings.
+func mergeConfig(dst, src *Config) {
+	if src.Server.Name != "" {
+		dst.Server.Name = src.Server.Name
+	}
+	if src.Server.Version != "" {
+		dst.Server.Version = src.Server.Version
+	}
+	if src.Server.Description != "" {
+		dst.Server.Description = src.Server.Description
+	}
+
+	if len(src.Headers) > 0 && dst.Headers == nil {
+		dst.Headers = make(map[string]string, len(src.Headers))
+	}
+	for k, v := range src.Headers {
+		dst.Headers[k] = v
+	}
+
+	if src.Transport.Type != "" {
+		dst.Transport = src.Transport
+	}
+
+	for _, api := range src.APIs {
+		mergeAPI(dst, api)
+	}
+}
+
+func mergeAPI(dst *Config, api APIConfig) {
+	for i := range dst.APIs {
+		if dst.APIs[i].Name != api.Name {
+			continue
+		}
+
+		if api.BaseURL != "" {
+			dst.APIs[i].BaseURL = api.BaseURL
+		}
+		if api.Timeout != 0 {
+			dst.APIs[i].Timeout = api.Timeout
+		}
+		if len(api.Auth) > 0 {
+			dst.APIs[i].Auth = api.Auth
+		}
+		if api.Policy != nil {
+			dst.APIs[i].Policy = api.Policy
+		}
+
+		if len(api.Headers) > 0 && dst.APIs[i].Headers == nil {
+			dst.APIs[i].Headers = make(map[string]string, len(api.Headers))
+		}
+		for k, v := range api.Headers {
+			dst.APIs[i].Headers[k] = v
+		}
+
+		for _, endpoint := range api.Endpoints {
+			mergeEndpoint(&dst.APIs[i], endpoint)
+		}
+		return
+	}
+
+	dst.APIs = append(dst.APIs, api)
+}
+
+// mergeEndpoint merges endpoint into api's existing entry of the same name
+// field by field, the same non-zero-overrides semantics as mergeAPI, so a
+// fragment that only patches an endpoint's Policy or Response doesn't wipe
+// the rest of its definition.
+func mergeEndpoint(api *APIConfig, endpoint CustomEndpoint) {
+	for i := range api.Endpoints {
+		if api.Endpoints[i].Name != endpoint.Name {
+			continue
+		}
+
+		existing := &api.Endpoints[i]
+		if endpoint.Description != "" {
+			existing.Description = endpoint.Description
+		}
+		if endpoint.Method != "" {
+			existing.Method = endpoint.Method
+		}
+		if endpoint.Path != "" {
+			existing.Path = endpoint.Path
+		}
+		if len(endpoint.Parameters) > 0 {
+			existing.Parameters = endpoint.Parameters
+		}
+
+		if len(endpoint.Headers) > 0 && existing.Headers == nil {
+			existing.Headers = make(map[string]string, len(endpoint.Headers))
+		}
+		for k, v := range endpoint.Headers {
+			existing.Headers[k] = v
+		}
+
+		if endpoint.Response != nil {
+			existing.Response = endpoint.Response
+		}
+		if endpoint.Policy != nil {
+			existing.Policy = endpoint.Policy
+		}
+		return
+	}
+	api.Endpoints = append(api.Endpoints, endpoint)
+}
@@ -0,0 +1,135 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestApplyIncludesMergesFragments(t *testing.T) {
+	dir := t.TempDir()
+
+	writeConfigFile(t, filepath.Join(dir, "base.json"), `{
+		"server": {"name": "base"},
+		"include": ["conf.d/*.json"],
+		"apis": [{"name": "svc", "baseUrl": "http://base"}]
+	}`)
+
+	mustMkdir(t, filepath.Join(dir, "conf.d"))
+	writeConfigFile(t, filepath.Join(dir, "conf.d", "svc.json"), `{
+		"apis": [{"name": "svc", "baseUrl": "http://overridden"}]
+	}`)
+
+	cfg, err := loadConfigFile(filepath.Join(dir, "base.json"))
+	if err != nil {
+		t.Fatalf("loadConfigFile: %v", err)
+	}
+	cfg, err = applyIncludes(cfg, filepath.Join(dir, "base.json"))
+	if err != nil {
+		t.Fatalf("applyIncludes: %v", err)
+	}
+
+	if len(cfg.APIs) != 1 {
+		t.Fatalf("APIs = %d, want 1", len(cfg.APIs))
+	}
+	if got := cfg.APIs[0].BaseURL; got != "http://overridden" {
+		t.Errorf("BaseURL = %q, want %q", got, "http://overridden")
+	}
+}
+
+func TestMergeAPICopiesPolicy(t *testing.T) {
+	dst := &Config{APIs: []APIConfig{{Name: "svc", BaseURL: "http://base"}}}
+	overlay := APIConfig{
+		Name:   "svc",
+		Policy: &Policy{RateLimit: &RateLimitPolicy{RPS: 5, Burst: 1}},
+	}
+
+	mergeAPI(dst, overlay)
+
+	if dst.APIs[0].Policy == nil || dst.APIs[0].Policy.RateLimit == nil {
+		t.Fatal("mergeAPI dropped the overlay's Policy")
+	}
+	if got := dst.APIs[0].Policy.RateLimit.RPS; got != 5 {
+		t.Errorf("RateLimit.RPS = %v, want 5", got)
+	}
+	if got := dst.APIs[0].BaseURL; got != "http://base" {
+		t.Errorf("BaseURL = %q, want the original %q to survive the merge", got, "http://base")
+	}
+}
+
+func TestMergeEndpointPreservesFieldsNotInOverlay(t *testing.T) {
+	api := &APIConfig{
+		Endpoints: []CustomEndpoint{{
+			Name:   "get-pet",
+			Method: "GET",
+			Path:   "/pets/{id}",
+		}},
+	}
+
+	mergeEndpoint(api, CustomEndpoint{
+		Name:   "get-pet",
+		Policy: &Policy{RateLimit: &RateLimitPolicy{RPS: 2, Burst: 1}},
+		Response: &ResponseConfig{
+			Rename: map[string]string{"id": "pet_id"},
+		},
+	})
+
+	got := api.Endpoints[0]
+	if got.Method != "GET" || got.Path != "/pets/{id}" {
+		t.Fatalf("mergeEndpoint wiped existing fields: %+v", got)
+	}
+	if got.Policy == nil || got.Policy.RateLimit == nil || got.Policy.RateLimit.RPS != 2 {
+		t.Fatalf("mergeEndpoint did not apply the overlay's Policy: %+v", got.Policy)
+	}
+	if got.Response == nil || got.Response.Rename["id"] != "pet_id" {
+		t.Fatalf("mergeEndpoint did not apply the overlay's Response: %+v", got.Response)
+	}
+}
+
+func TestApplyIncludesDetectsCycle(t *testing.T) {
+	dir := t.TempDir()
+
+	writeConfigFile(t, filepath.Join(dir, "a.json"), `{
+		"include": ["b.json"],
+		"apis": [{"name": "svc", "baseUrl": "http://a"}]
+	}`)
+	writeConfigFile(t, filepath.Join(dir, "b.json"), `{
+		"include": ["a.json"],
+		"apis": [{"name": "svc", "baseUrl": "http://b"}]
+	}`)
+
+	cfg, err := loadConfigFile(filepath.Join(dir, "a.json"))
+	if err != nil {
+		t.Fatalf("loadConfigFile: %v", err)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := applyIncludes(cfg, filepath.Join(dir, "a.json"))
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Fatal("applyIncludes succeeded on a cyclic include chain, want error")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("applyIncludes did not return, likely recursing on the include cycle")
+	}
+}
+
+func writeConfigFile(t *testing.T, path, contents string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(contents), 0644); err != nil {
+		t.Fatalf("writing %s: %v", path, err)
+	}
+}
+
+func mustMkdir(t *testing.T, path string) {
+	t.Helper()
+	if err := os.MkdirAll(path, 0755); err != nil {
+		t.Fatalf("mkdir %s: %v", path, err)
+	}
+}
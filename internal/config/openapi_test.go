@@ -0,0 +1,81 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const testOpenAPIDoc = `
+openapi: 3.0.0
+info:
+  title: Pet Store
+  version: "1.0.0"
+servers:
+  - url: https://api.example.com/v1
+components:
+  securitySchemes:
+    bearerAuth:
+      type: http
+      scheme: bearer
+paths:
+  /pets/{petId}:
+    get:
+      operationId: getPet
+      summary: Get a pet by ID
+      parameters:
+        - name: petId
+          in: path
+          required: true
+          schema:
+            type: string
+        - name: verbose
+          in: query
+          required: false
+          schema:
+            type: boolean
+            default: false
+      responses:
+        "200":
+          description: OK
+`
+
+func TestLoadFromOpenAPI(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "petstore.yaml")
+	if err := os.WriteFile(path, []byte(testOpenAPIDoc), 0644); err != nil {
+		t.Fatalf("writing spec: %v", err)
+	}
+
+	api, err := LoadFromOpenAPI(path)
+	if err != nil {
+		t.Fatalf("LoadFromOpenAPI: %v", err)
+	}
+
+	if got, want := api.Name, "pet-store"; got != want {
+		t.Errorf("Name = %q, want %q", got, want)
+	}
+	if got, want := api.BaseURL, "https://api.example.com/v1"; got != want {
+		t.Errorf("BaseURL = %q, want %q", got, want)
+	}
+	if len(api.Endpoints) != 1 {
+		t.Fatalf("Endpoints = %d, want 1", len(api.Endpoints))
+	}
+
+	endpoint := api.Endpoints[0]
+	if got, want := endpoint.Name, "getPet"; got != want {
+		t.Errorf("Endpoint name = %q, want %q", got, want)
+	}
+	if got, want := endpoint.Method, "GET"; got != want {
+		t.Errorf("Endpoint method = %q, want %q", got, want)
+	}
+	if got, want := endpoint.Path, "/pets/{petId}"; got != want {
+		t.Errorf("Endpoint path = %q, want %q", got, want)
+	}
+	if len(endpoint.Parameters) != 2 {
+		t.Fatalf("Parameters = %d, want 2", len(endpoint.Parameters))
+	}
+
+	if len(api.Auth) != 1 || api.Auth[0].Type != "bearer" {
+		t.Errorf("Auth = %+v, want a single bearer scheme", api.Auth)
+	}
+}
@@ -0,0 +1,285 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// Policy bundles the resiliency knobs an API (or a single endpoint, as an
+// override) can declare against its upstream: a token-bucket rate limit, a
+// retry policy, and a circuit breaker. Compiled/derived state (the limiter,
+// parsed durations, breaker state) is filled in by validate and kept in
+// memory per Policy instance.
+type Policy struct {
+	RateLimit      *RateLimitPolicy      `json:"rateLimit,omitempty" yaml:"rateLimit,omitempty"`
+	Retry          *RetryPolicy          `json:"retry,omitempty" yaml:"retry,omitempty"`
+	CircuitBreaker *CircuitBreakerPolicy `json:"circuitBreaker,omitempty" yaml:"circuitBreaker,omitempty"`
+}
+
+// RateLimitPolicy is a token-bucket limit: rps tokens refill per second, up
+// to burst tokens held at once.
+type RateLimitPolicy struct {
+	RPS   float64 `json:"rps" yaml:"rps"`
+	Burst int     `json:"burst" yaml:"burst"`
+
+	limiter *rate.Limiter
+}
+
+// RetryPolicy controls retries of failed upstream requests.
+type RetryPolicy struct {
+	MaxAttempts int    `json:"maxAttempts" yaml:"maxAttempts"`
+	Backoff     string `json:"backoff" yaml:"backoff"`
+	RetryOn     []int  `json:"retryOn,omitempty" yaml:"retryOn,omitempty"`
+
+	backoff time.Duration
+}
+
+// CircuitBreakerPolicy trips after failureThreshold consecutive failures,
+// stays open for openDuration, then allows halfOpenProbes trial requests
+// through before fully closing or re-opening.
+type CircuitBreakerPolicy struct {
+	FailureThreshold int    `json:"failureThreshold" yaml:"failureThreshold"`
+	OpenDuration     string `json:"openDuration" yaml:"openDuration"`
+	HalfOpenProbes   int    `json:"halfOpenProbes" yaml:"halfOpenProbes"`
+
+	openDuration time.Duration
+	state        *circuitBreakerState
+}
+
+// validate fills in defaults, parses duration strings, and builds the
+// in-memory rate limiter / breaker state so the dispatcher can use the
+// policy directly after Config.Validate succeeds.
+func (p *Policy) validate() error {
+	if p == nil {
+		return nil
+	}
+
+	if p.RateLimit != nil {
+		if p.RateLimit.RPS <= 0 {
+			return fmt.Errorf("rate limit: rps must be positive")
+		}
+		if p.RateLimit.Burst <= 0 {
+			p.RateLimit.Burst = 1
+		}
+		p.RateLimit.limiter = rate.NewLimiter(rate.Limit(p.RateLimit.RPS), p.RateLimit.Burst)
+	}
+
+	if p.Retry != nil {
+		if p.Retry.MaxAttempts <= 0 {
+			p.Retry.MaxAttempts = 1
+		}
+		if p.Retry.Backoff == "" {
+			p.Retry.Backoff = "200ms"
+		}
+		backoff, err := time.ParseDuration(p.Retry.Backoff)
+		if err != nil {
+			return fmt.Errorf("retry: invalid backoff %q: %w", p.Retry.Backoff, err)
+		}
+		p.Retry.backoff = backoff
+	}
+
+	if p.CircuitBreaker != nil {
+		if p.CircuitBreaker.FailureThreshold <= 0 {
+			return fmt.Errorf("circuit breaker: failureThreshold must be positive")
+		}
+		if p.CircuitBreaker.HalfOpenProbes <= 0 {
+			p.CircuitBreaker.HalfOpenProbes = 1
+		}
+		if p.CircuitBreaker.OpenDuration == "" {
+			p.CircuitBreaker.OpenDuration = "30s"
+		}
+		openDuration, err := time.ParseDuration(p.CircuitBreaker.OpenDuration)
+		if err != nil {
+			return fmt.Errorf("circuit breaker: invalid openDuration %q: %w", p.CircuitBreaker.OpenDuration, err)
+		}
+		p.CircuitBreaker.openDuration = openDuration
+		p.CircuitBreaker.state = &circuitBreakerState{}
+	}
+
+	return nil
+}
+
+// Do sends req via client, honoring p's rate limit, retry, and circuit
+// breaker policy: it waits for the rate limiter before each attempt, gates
+// on the circuit breaker, and retries per Retry.ShouldRetry/Delay, feeding
+// each attempt's outcome back into the breaker. A nil Policy sends req
+// once with no extra behavior, the same no-op semantics its component
+// policies already have individually.
+func (p *Policy) Do(ctx context.Context, client *http.Client, req *http.Request) (*http.Response, error) {
+	if p == nil {
+		return client.Do(req.WithContext(ctx))
+	}
+
+	if !p.CircuitBreaker.Allow() {
+		return nil, fmt.Errorf("circuit breaker open")
+	}
+
+	for attempt := 0; ; attempt++ {
+		if err := p.RateLimit.Wait(ctx); err != nil {
+			return nil, err
+		}
+
+		attemptReq := req.Clone(ctx)
+		if attempt > 0 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, fmt.Errorf("rebuilding request body for retry: %w", err)
+			}
+			attemptReq.Body = body
+		}
+
+		resp, err := client.Do(attemptReq)
+		if err != nil {
+			p.CircuitBreaker.RecordFailure()
+			if p.Retry.ShouldRetry(attempt, 0) {
+				time.Sleep(p.Retry.Delay(attempt))
+				continue
+			}
+			return nil, err
+		}
+
+		if p.Retry.ShouldRetry(attempt, resp.StatusCode) {
+			p.CircuitBreaker.RecordFailure()
+			resp.Body.Close()
+			time.Sleep(p.Retry.Delay(attempt))
+			continue
+		}
+
+		// A 5xx counts against the breaker even with no Retry configured
+		// (or once retries are exhausted), since it's still evidence the
+		// upstream is unhealthy.
+		if resp.StatusCode >= 500 {
+			p.CircuitBreaker.RecordFailure()
+		} else {
+			p.CircuitBreaker.RecordSuccess()
+		}
+		return resp, nil
+	}
+}
+
+// Wait blocks until the rate limiter admits a request, or ctx is canceled.
+func (p *RateLimitPolicy) Wait(ctx context.Context) error {
+	if p == nil || p.limiter == nil {
+		return nil
+	}
+	return p.limiter.Wait(ctx)
+}
+
+// ShouldRetry reports whether attempt (0-based) should be retried given the
+// upstream's response status code.
+func (p *RetryPolicy) ShouldRetry(attempt, statusCode int) bool {
+	if p == nil {
+		return false
+	}
+	if attempt+1 >= p.MaxAttempts {
+		return false
+	}
+	if len(p.RetryOn) == 0 {
+		return statusCode >= 500
+	}
+	for _, code := range p.RetryOn {
+		if code == statusCode {
+			return true
+		}
+	}
+	return false
+}
+
+// Delay returns the backoff to wait before the given (0-based) retry
+// attempt, doubling each time.
+func (p *RetryPolicy) Delay(attempt int) time.Duration {
+	if p == nil {
+		return 0
+	}
+	return p.backoff * time.Duration(1<<uint(attempt))
+}
+
+type circuitState int
+
+const (
+	circuitClosed circuitState = iota
+	circuitOpen
+	circuitHalfOpen
+)
+
+type circuitBreakerState struct {
+	mu           sync.Mutex
+	state        circuitState
+	failures     int
+	openedAt     time.Time
+	halfOpenLeft int
+}
+
+// Allow reports whether a request may proceed, transitioning the breaker
+// from open to half-open once openDuration has elapsed.
+func (cb *CircuitBreakerPolicy) Allow() bool {
+	if cb == nil || cb.state == nil {
+		return true
+	}
+
+	s := cb.state
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch s.state {
+	case circuitOpen:
+		if time.Since(s.openedAt) < cb.openDuration {
+			return false
+		}
+		s.state = circuitHalfOpen
+		s.halfOpenLeft = cb.HalfOpenProbes
+		fallthrough
+	case circuitHalfOpen:
+		if s.halfOpenLeft <= 0 {
+			return false
+		}
+		s.halfOpenLeft--
+		return true
+	default:
+		return true
+	}
+}
+
+// RecordSuccess closes the breaker and resets its failure count.
+func (cb *CircuitBreakerPolicy) RecordSuccess() {
+	if cb == nil || cb.state == nil {
+		return
+	}
+
+	s := cb.state
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.failures = 0
+	s.state = circuitClosed
+}
+
+// RecordFailure counts a failure, opening the breaker once
+// FailureThreshold is reached (or immediately, if a half-open probe
+// failed).
+func (cb *CircuitBreakerPolicy) RecordFailure() {
+	if cb == nil || cb.state == nil {
+		return
+	}
+
+	s := cb.state
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.state == circuitHalfOpen {
+		s.state = circuitOpen
+		s.openedAt = time.Now()
+		return
+	}
+
+	s.failures++
+	if s.failures >= cb.FailureThreshold {
+		s.state = circuitOpen
+		s.openedAt = time.Now()
+	}
+}
@@ -0,0 +1,172 @@
+package config
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreakerPolicyNilStateDoesNotPanic(t *testing.T) {
+	cb := &CircuitBreakerPolicy{FailureThreshold: 3}
+
+	if !cb.Allow() {
+		t.Error("Allow on an unvalidated breaker = false, want true (fail open)")
+	}
+	cb.RecordSuccess()
+	cb.RecordFailure()
+}
+
+func TestCircuitBreakerPolicyTripsAndRecovers(t *testing.T) {
+	cb := &CircuitBreakerPolicy{FailureThreshold: 2, HalfOpenProbes: 1}
+	p := &Policy{CircuitBreaker: cb}
+	if err := p.validate(); err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+
+	if !cb.Allow() {
+		t.Fatal("Allow before any failures = false, want true")
+	}
+
+	cb.RecordFailure()
+	if !cb.Allow() {
+		t.Fatal("Allow after one failure (below threshold) = false, want true")
+	}
+
+	cb.RecordFailure()
+	if cb.Allow() {
+		t.Fatal("Allow after hitting FailureThreshold = true, want false (open)")
+	}
+
+	cb.state.openedAt = time.Now().Add(-cb.openDuration)
+	if !cb.Allow() {
+		t.Fatal("Allow after openDuration elapsed = false, want true (half-open probe)")
+	}
+	if cb.Allow() {
+		t.Fatal("Allow with no half-open probes left = true, want false")
+	}
+}
+
+func TestRetryPolicyShouldRetry(t *testing.T) {
+	p := &RetryPolicy{MaxAttempts: 3, RetryOn: []int{429, 503}}
+	if err := (&Policy{Retry: p}).validate(); err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+
+	if !p.ShouldRetry(0, 503) {
+		t.Error("ShouldRetry(0, 503) = false, want true")
+	}
+	if p.ShouldRetry(0, 404) {
+		t.Error("ShouldRetry(0, 404) = true, want false (not in RetryOn)")
+	}
+	if p.ShouldRetry(2, 503) {
+		t.Error("ShouldRetry(2, 503) = true, want false (at MaxAttempts)")
+	}
+}
+
+func TestRateLimitPolicyWait(t *testing.T) {
+	p := &RateLimitPolicy{RPS: 1000, Burst: 1}
+	if err := (&Policy{RateLimit: p}).validate(); err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+	if err := p.Wait(context.Background()); err != nil {
+		t.Fatalf("Wait: %v", err)
+	}
+}
+
+func TestNilPoliciesAreNoOps(t *testing.T) {
+	var rl *RateLimitPolicy
+	if err := rl.Wait(context.Background()); err != nil {
+		t.Errorf("nil RateLimitPolicy.Wait returned %v, want nil", err)
+	}
+
+	var retry *RetryPolicy
+	if retry.ShouldRetry(0, 500) {
+		t.Error("nil RetryPolicy.ShouldRetry = true, want false")
+	}
+	if retry.Delay(0) != 0 {
+		t.Error("nil RetryPolicy.Delay != 0")
+	}
+
+	var cb *CircuitBreakerPolicy
+	if !cb.Allow() {
+		t.Error("nil CircuitBreakerPolicy.Allow = false, want true")
+	}
+	cb.RecordSuccess()
+	cb.RecordFailure()
+}
+
+// TestPolicyDoRetriesOnFailure verifies that Policy.Do retries a request
+// that comes back with a retryable status, and returns the eventual
+// success to the caller.
+func TestPolicyDoRetriesOnFailure(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	p := &Policy{Retry: &RetryPolicy{MaxAttempts: 3, Backoff: "1ms", RetryOn: []int{503}}}
+	if err := p.validate(); err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+
+	req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+	if err != nil {
+		t.Fatalf("NewRequest: %v", err)
+	}
+
+	resp, err := p.Do(context.Background(), server.Client(), req)
+	if err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+	resp.Body.Close()
+
+	if got := atomic.LoadInt32(&requests); got != 3 {
+		t.Errorf("requests received = %d, want 3", got)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("final status = %d, want 200", resp.StatusCode)
+	}
+}
+
+// TestPolicyDoOpenCircuitBreakerFailsFast verifies that once the circuit
+// breaker has tripped, Do refuses to send further requests at all.
+func TestPolicyDoOpenCircuitBreakerFailsFast(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	p := &Policy{CircuitBreaker: &CircuitBreakerPolicy{FailureThreshold: 1}}
+	if err := p.validate(); err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+
+	req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+	if err != nil {
+		t.Fatalf("NewRequest: %v", err)
+	}
+
+	resp, err := p.Do(context.Background(), server.Client(), req)
+	if err != nil {
+		t.Fatalf("first Do: %v", err)
+	}
+	resp.Body.Close()
+
+	if _, err := p.Do(context.Background(), server.Client(), req); err == nil {
+		t.Fatal("Do with an open circuit breaker succeeded, want error")
+	}
+
+	if got := atomic.LoadInt32(&requests); got != 1 {
+		t.Errorf("requests received = %d, want 1 (second Do should fail fast)", got)
+	}
+}
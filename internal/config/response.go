@@ -0,0 +1,219 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/itchyny/gojq"
+)
+
+// ResponseConfig describes how an endpoint's raw HTTP response should be
+// reshaped before being returned to the MCP client, so noisy REST
+// responses can be exposed as clean, purpose-built tools.
+type ResponseConfig struct {
+	// Extract is a gojq expression (jq syntax, e.g. ".data.items") run
+	// against the decoded JSON body to select the subset returned to the
+	// client.
+	Extract string `json:"extract,omitempty" yaml:"extract,omitempty"`
+
+	// Rename maps result field names to the names they should be
+	// reported under, applied after Extract.
+	Rename map[string]string `json:"rename,omitempty" yaml:"rename,omitempty"`
+
+	Pagination *PaginationConfig `json:"pagination,omitempty" yaml:"pagination,omitempty"`
+
+	// ErrorMap maps upstream HTTP status codes to the MCP error message
+	// returned in their place.
+	ErrorMap map[int]string `json:"errorMap,omitempty" yaml:"errorMap,omitempty"`
+
+	compiled *gojq.Code `json:"-" yaml:"-"`
+}
+
+// PaginationConfig follows a REST API's Link-header pagination, concatenating
+// pages until the relation is absent or MaxPages is reached.
+type PaginationConfig struct {
+	// FollowLinkHeader is the Link rel to follow, e.g. "next". Defaults to
+	// "next".
+	FollowLinkHeader string `json:"followLinkHeader,omitempty" yaml:"followLinkHeader,omitempty"`
+
+	// MaxPages caps how many pages are followed. Defaults to 1 (no
+	// follow-up requests).
+	MaxPages int `json:"maxPages,omitempty" yaml:"maxPages,omitempty"`
+}
+
+// validate fills in pagination defaults, checks ErrorMap's status codes,
+// and compiles Extract so a bad expression fails at load time rather than
+// on the first request.
+func (r *ResponseConfig) validate() error {
+	if r == nil {
+		return nil
+	}
+
+	if r.Extract != "" {
+		if err := r.compile(); err != nil {
+			return err
+		}
+	}
+
+	if r.Pagination != nil {
+		if r.Pagination.FollowLinkHeader == "" {
+			r.Pagination.FollowLinkHeader = "next"
+		}
+		if r.Pagination.MaxPages <= 0 {
+			r.Pagination.MaxPages = 1
+		}
+	}
+
+	for status := range r.ErrorMap {
+		if status < 100 || status > 599 {
+			return fmt.Errorf("error map status %d is not a valid HTTP status code", status)
+		}
+	}
+
+	return nil
+}
+
+func (r *ResponseConfig) compile() error {
+	query, err := gojq.Parse(r.Extract)
+	if err != nil {
+		return fmt.Errorf("parsing extract expression %q: %w", r.Extract, err)
+	}
+
+	code, err := gojq.Compile(query)
+	if err != nil {
+		return fmt.Errorf("compiling extract expression %q: %w", r.Extract, err)
+	}
+
+	r.compiled = code
+	return nil
+}
+
+// Query returns the compiled gojq program backing Extract, compiling it on
+// first use if Validate has not already done so.
+func (r *ResponseConfig) Query() (*gojq.Code, error) {
+	if r.Extract == "" {
+		return nil, nil
+	}
+	if r.compiled == nil {
+		if err := r.compile(); err != nil {
+			return nil, err
+		}
+	}
+	return r.compiled, nil
+}
+
+// Apply runs resp through r's pipeline: mapping a known error status to its
+// configured message, extracting and renaming the JSON body, and following
+// Link-header pagination (via client) up to Pagination.MaxPages. With no
+// Pagination configured, or MaxPages of 1, the result is just the first
+// page's extracted value; otherwise it's a slice of one value per page.
+func (r *ResponseConfig) Apply(client *http.Client, resp *http.Response) (interface{}, error) {
+	if msg, ok := r.ErrorMap[resp.StatusCode]; ok {
+		resp.Body.Close()
+		return nil, fmt.Errorf("%s", msg)
+	}
+
+	result, err := r.extractPage(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.Pagination == nil || r.Pagination.MaxPages <= 1 {
+		return result, nil
+	}
+
+	results := []interface{}{result}
+	next := nextPageURL(resp, r.Pagination.FollowLinkHeader)
+	for page := 1; page < r.Pagination.MaxPages && next != ""; page++ {
+		pageResp, err := client.Get(next)
+		if err != nil {
+			return nil, fmt.Errorf("following pagination link: %w", err)
+		}
+		if msg, ok := r.ErrorMap[pageResp.StatusCode]; ok {
+			pageResp.Body.Close()
+			return nil, fmt.Errorf("%s", msg)
+		}
+		pageResult, err := r.extractPage(pageResp)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, pageResult)
+		next = nextPageURL(pageResp, r.Pagination.FollowLinkHeader)
+	}
+
+	return results, nil
+}
+
+// extractPage decodes, extracts, and renames a single page's JSON body. It
+// always closes resp.Body.
+func (r *ResponseConfig) extractPage(resp *http.Response) (interface{}, error) {
+	defer resp.Body.Close()
+
+	var decoded interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&decoded); err != nil {
+		return nil, fmt.Errorf("decoding response body: %w", err)
+	}
+
+	if r.Extract != "" {
+		code, err := r.Query()
+		if err != nil {
+			return nil, err
+		}
+		iter := code.Run(decoded)
+		v, ok := iter.Next()
+		if !ok {
+			return nil, fmt.Errorf("extract expression %q produced no output", r.Extract)
+		}
+		if err, ok := v.(error); ok {
+			return nil, fmt.Errorf("extract expression %q: %w", r.Extract, err)
+		}
+		decoded = v
+	}
+
+	return renameFields(decoded, r.Rename), nil
+}
+
+// renameFields renames v's top-level keys per rename when v decodes to a
+// JSON object; other shapes (arrays, scalars) pass through unchanged since
+// there are no field names to rename.
+func renameFields(v interface{}, rename map[string]string) interface{} {
+	if len(rename) == 0 {
+		return v
+	}
+	obj, ok := v.(map[string]interface{})
+	if !ok {
+		return v
+	}
+
+	renamed := make(map[string]interface{}, len(obj))
+	for k, val := range obj {
+		if to, ok := rename[k]; ok {
+			renamed[to] = val
+		} else {
+			renamed[k] = val
+		}
+	}
+	return renamed
+}
+
+// nextPageURL returns the URL of resp's Link header entry for rel, or ""
+// if the header is absent or has no matching entry.
+func nextPageURL(resp *http.Response, rel string) string {
+	want := `rel="` + rel + `"`
+	for _, header := range resp.Header.Values("Link") {
+		for _, part := range strings.Split(header, ",") {
+			segments := strings.Split(part, ";")
+			if len(segments) < 2 {
+				continue
+			}
+			for _, seg := range segments[1:] {
+				if strings.TrimSpace(seg) == want {
+					return strings.Trim(strings.TrimSpace(segments[0]), "<>")
+				}
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,163 @@
+package config
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestResponseConfigValidateCompilesExtract(t *testing.T) {
+	r := &ResponseConfig{Extract: ".data.items"}
+	if err := r.validate(); err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+
+	code, err := r.Query()
+	if err != nil {
+		t.Fatalf("Query: %v", err)
+	}
+	if code == nil {
+		t.Fatal("Query returned nil code for a non-empty Extract")
+	}
+
+	iter := code.Run(map[string]interface{}{
+		"data": map[string]interface{}{"items": []interface{}{"a", "b"}},
+	})
+	v, ok := iter.Next()
+	if !ok {
+		t.Fatal("gojq program produced no output")
+	}
+	if err, ok := v.(error); ok {
+		t.Fatalf("gojq program failed: %v", err)
+	}
+	got, ok := v.([]interface{})
+	if !ok || len(got) != 2 {
+		t.Fatalf("extracted = %#v, want a 2-element slice", v)
+	}
+}
+
+func TestResponseConfigValidateRejectsBadExtract(t *testing.T) {
+	r := &ResponseConfig{Extract: "not ( valid jq"}
+	if err := r.validate(); err == nil {
+		t.Fatal("validate succeeded on an invalid jq expression, want error")
+	}
+}
+
+func TestResponseConfigValidateRejectsBadStatusCode(t *testing.T) {
+	r := &ResponseConfig{ErrorMap: map[int]string{999: "nope"}}
+	if err := r.validate(); err == nil {
+		t.Fatal("validate succeeded with an out-of-range status code, want error")
+	}
+}
+
+func TestResponseConfigValidateFillsPaginationDefaults(t *testing.T) {
+	r := &ResponseConfig{Pagination: &PaginationConfig{}}
+	if err := r.validate(); err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+	if got, want := r.Pagination.FollowLinkHeader, "next"; got != want {
+		t.Errorf("FollowLinkHeader = %q, want %q", got, want)
+	}
+	if got, want := r.Pagination.MaxPages, 1; got != want {
+		t.Errorf("MaxPages = %d, want %d", got, want)
+	}
+}
+
+func TestResponseConfigApplyRenamesExtractedFields(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"id": 1, "name": "widget"}`))
+	}))
+	defer server.Close()
+
+	r := &ResponseConfig{Rename: map[string]string{"id": "widget_id"}}
+	if err := r.validate(); err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+
+	resp, err := server.Client().Get(server.URL)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+
+	got, err := r.Apply(server.Client(), resp)
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+
+	obj, ok := got.(map[string]interface{})
+	if !ok {
+		t.Fatalf("Apply result = %#v, want a map", got)
+	}
+	if _, has := obj["id"]; has {
+		t.Errorf("result still has the original key %q: %#v", "id", obj)
+	}
+	if obj["widget_id"] != float64(1) {
+		t.Errorf("widget_id = %#v, want 1", obj["widget_id"])
+	}
+	if obj["name"] != "widget" {
+		t.Errorf("name = %#v, want %q", obj["name"], "widget")
+	}
+}
+
+func TestResponseConfigApplyMapsErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	r := &ResponseConfig{ErrorMap: map[int]string{429: "rate limited by upstream"}}
+	if err := r.validate(); err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+
+	resp, err := server.Client().Get(server.URL)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+
+	if _, err := r.Apply(server.Client(), resp); err == nil || err.Error() != "rate limited by upstream" {
+		t.Fatalf("Apply error = %v, want %q", err, "rate limited by upstream")
+	}
+}
+
+func TestResponseConfigApplyFollowsPagination(t *testing.T) {
+	var mux http.ServeMux
+	server := httptest.NewServer(&mux)
+	defer server.Close()
+
+	mux.HandleFunc("/page1", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Link", fmt.Sprintf(`<%s/page2>; rel="next"`, server.URL))
+		w.Write([]byte(`{"page": 1}`))
+	})
+	mux.HandleFunc("/page2", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"page": 2}`))
+	})
+
+	r := &ResponseConfig{Pagination: &PaginationConfig{MaxPages: 2}}
+	if err := r.validate(); err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+
+	resp, err := server.Client().Get(server.URL + "/page1")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+
+	got, err := r.Apply(server.Client(), resp)
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+
+	pages, ok := got.([]interface{})
+	if !ok || len(pages) != 2 {
+		t.Fatalf("Apply result = %#v, want a 2-element slice", got)
+	}
+	if pages[0].(map[string]interface{})["page"] != float64(1) {
+		t.Errorf("page 1 = %#v, want page 1", pages[0])
+	}
+	if pages[1].(map[string]interface{})["page"] != float64(2) {
+		t.Errorf("page 2 = %#v, want page 2", pages[1])
+	}
+}
@@ -0,0 +1,162 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// SecretResolver resolves the argument of a secret reference (the part
+// after the scheme, e.g. "VAR" in "${env:VAR}") into its concrete value.
+// Register additional backends such as Vault or AWS Secrets Manager with
+// RegisterSecretResolver.
+type SecretResolver interface {
+	Resolve(arg string) (string, error)
+}
+
+// SecretResolverFunc adapts a function to the SecretResolver interface.
+type SecretResolverFunc func(arg string) (string, error)
+
+func (f SecretResolverFunc) Resolve(arg string) (string, error) {
+	return f(arg)
+}
+
+var secretRefPattern = regexp.MustCompile(`\$\{([A-Za-z0-9_]+):([^}]*)\}`)
+
+var (
+	secretResolversMu sync.RWMutex
+	secretResolvers   = map[string]SecretResolver{
+		"env":  SecretResolverFunc(resolveEnvSecret),
+		"file": SecretResolverFunc(resolveFileSecret),
+		"cmd":  SecretResolverFunc(resolveCmdSecret),
+	}
+)
+
+// RegisterSecretResolver registers a resolver for the given scheme (the
+// prefix before the colon in "${scheme:arg}"), overriding any existing
+// resolver for that scheme.
+func RegisterSecretResolver(scheme string, resolver SecretResolver) {
+	secretResolversMu.Lock()
+	defer secretResolversMu.Unlock()
+	secretResolvers[scheme] = resolver
+}
+
+// resolveSecrets expands ${scheme:arg} secret references found in the
+// config's headers and authentication credentials, in place.
+func resolveSecrets(c *Config) error {
+	if err := resolveStringMap(c.Headers); err != nil {
+		return err
+	}
+
+	for i := range c.APIs {
+		api := &c.APIs[i]
+
+		if err := resolveStringMap(api.Headers); err != nil {
+			return fmt.Errorf("API %s: %w", api.Name, err)
+		}
+
+		for j := range api.Auth {
+			auth := &api.Auth[j]
+			var err error
+			switch {
+			case auth.Basic != nil:
+				auth.Basic.Password, err = resolveSecretString(auth.Basic.Password)
+			case auth.Bearer != nil:
+				auth.Bearer.Token, err = resolveSecretString(auth.Bearer.Token)
+			case auth.APIKey != nil:
+				auth.APIKey.Key, err = resolveSecretString(auth.APIKey.Key)
+			case auth.OAuth2 != nil:
+				auth.OAuth2.ClientSecret, err = resolveSecretString(auth.OAuth2.ClientSecret)
+			}
+			if err != nil {
+				return fmt.Errorf("API %s, auth index %d: %w", api.Name, j, err)
+			}
+		}
+
+		for k := range api.Endpoints {
+			if err := resolveStringMap(api.Endpoints[k].Headers); err != nil {
+				return fmt.Errorf("API %s, endpoint %s: %w", api.Name, api.Endpoints[k].Name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func resolveStringMap(m map[string]string) error {
+	for k, v := range m {
+		resolved, err := resolveSecretString(v)
+		if err != nil {
+			return err
+		}
+		m[k] = resolved
+	}
+	return nil
+}
+
+func resolveSecretString(s string) (string, error) {
+	if !strings.Contains(s, "${") {
+		return s, nil
+	}
+
+	var resolveErr error
+	result := secretRefPattern.ReplaceAllStringFunc(s, func(match string) string {
+		if resolveErr != nil {
+			return match
+		}
+
+		groups := secretRefPattern.FindStringSubmatch(match)
+		scheme, arg := groups[1], groups[2]
+
+		secretResolversMu.RLock()
+		resolver, ok := secretResolvers[scheme]
+		secretResolversMu.RUnlock()
+		if !ok {
+			resolveErr = fmt.Errorf("unknown secret reference scheme %q in %q", scheme, match)
+			return match
+		}
+
+		resolved, err := resolver.Resolve(arg)
+		if err != nil {
+			resolveErr = fmt.Errorf("resolving %q: %w", match, err)
+			return match
+		}
+		return resolved
+	})
+
+	if resolveErr != nil {
+		return "", resolveErr
+	}
+	return result, nil
+}
+
+func resolveEnvSecret(arg string) (string, error) {
+	val, ok := os.LookupEnv(arg)
+	if !ok {
+		return "", fmt.Errorf("environment variable %q is not set", arg)
+	}
+	return val, nil
+}
+
+func resolveFileSecret(arg string) (string, error) {
+	data, err := os.ReadFile(arg)
+	if err != nil {
+		return "", fmt.Errorf("reading secret file %q: %w", arg, err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+func resolveCmdSecret(arg string) (string, error) {
+	parts := strings.Fields(arg)
+	if len(parts) == 0 {
+		return "", fmt.Errorf("empty cmd secret reference")
+	}
+	out, err := exec.Command(parts[0], parts[1:]...).Output()
+	if err != nil {
+		return "", fmt.Errorf("running command %q: %w", arg, err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
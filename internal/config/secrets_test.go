@@ -0,0 +1,87 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestResolveSecretString(t *testing.T) {
+	t.Setenv("MCPIFY_TEST_SECRET", "from-env")
+
+	secretFile := filepath.Join(t.TempDir(), "secret.txt")
+	if err := os.WriteFile(secretFile, []byte("from-file\n"), 0644); err != nil {
+		t.Fatalf("writing secret file: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{name: "plain value", in: "plaintext", want: "plaintext"},
+		{name: "env reference", in: "${env:MCPIFY_TEST_SECRET}", want: "from-env"},
+		{name: "file reference", in: "${file:" + secretFile + "}", want: "from-file"},
+		{name: "unknown scheme", in: "${vault:secret/foo}", wantErr: true},
+		{name: "missing env var", in: "${env:MCPIFY_TEST_SECRET_MISSING}", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolveSecretString(tt.in)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("resolveSecretString(%q) succeeded, want error", tt.in)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("resolveSecretString(%q): %v", tt.in, err)
+			}
+			if got != tt.want {
+				t.Errorf("resolveSecretString(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegisterSecretResolver(t *testing.T) {
+	RegisterSecretResolver("static", SecretResolverFunc(func(arg string) (string, error) {
+		return "resolved-" + arg, nil
+	}))
+
+	got, err := resolveSecretString("${static:foo}")
+	if err != nil {
+		t.Fatalf("resolveSecretString: %v", err)
+	}
+	if want := "resolved-foo"; got != want {
+		t.Errorf("resolveSecretString = %q, want %q", got, want)
+	}
+}
+
+func TestResolveSecretsAcrossConfig(t *testing.T) {
+	t.Setenv("MCPIFY_TEST_PASSWORD", "hunter2")
+
+	cfg := &Config{
+		Headers: map[string]string{"X-Token": "${env:MCPIFY_TEST_PASSWORD}"},
+		APIs: []APIConfig{
+			{
+				Name: "api",
+				Auth: []AuthConfig{
+					{Type: "basic", Basic: &BasicAuthConfig{Username: "u", Password: "${env:MCPIFY_TEST_PASSWORD}"}},
+				},
+			},
+		},
+	}
+
+	if err := resolveSecrets(cfg); err != nil {
+		t.Fatalf("resolveSecrets: %v", err)
+	}
+	if got := cfg.Headers["X-Token"]; got != "hunter2" {
+		t.Errorf("Headers[X-Token] = %q, want %q", got, "hunter2")
+	}
+	if got := cfg.APIs[0].Auth[0].Basic.Password; got != "hunter2" {
+		t.Errorf("Basic.Password = %q, want %q", got, "hunter2")
+	}
+}
@@ -0,0 +1,194 @@
+package config
+
+import (
+	"fmt"
+	"log"
+	"path/filepath"
+	"sync"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// Watcher watches a config file (and any files it includes) for changes,
+// re-loading and re-validating on write and publishing the new *Config
+// through Changes(). On a load or validation failure the previous config
+// is kept in effect and the error is delivered through Errors() instead,
+// so a bad edit never drops a running MCP bridge's active config.
+type Watcher struct {
+	path    string
+	watcher *fsnotify.Watcher
+
+	mu      sync.RWMutex
+	current *Config
+	watched map[string]bool
+
+	changes chan *Config
+	errors  chan error
+	done    chan struct{}
+}
+
+// NewWatcher loads path and starts watching it (and its includes) for
+// changes. Call Close when done to release the underlying fsnotify watcher.
+func NewWatcher(path string) (*Watcher, error) {
+	config, err := LoadConfig(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("initial config is invalid: %w", err)
+	}
+
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("creating file watcher: %w", err)
+	}
+
+	w := &Watcher{
+		path:    path,
+		watcher: fsw,
+		current: config,
+		watched: make(map[string]bool),
+		changes: make(chan *Config, 1),
+		errors:  make(chan error, 1),
+		done:    make(chan struct{}),
+	}
+
+	if err := w.watchFiles(); err != nil {
+		fsw.Close()
+		return nil, err
+	}
+
+	go w.run()
+
+	return w, nil
+}
+
+// Current returns the most recently loaded, validated config.
+func (w *Watcher) Current() *Config {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.current
+}
+
+// Changes delivers a new config each time the watched files change and the
+// result passes Validate. A config not yet received when the next one
+// arrives is replaced by the newer one.
+func (w *Watcher) Changes() <-chan *Config {
+	return w.changes
+}
+
+// Errors delivers load/validation failures encountered while watching; see
+// Current for the config that stays in effect when one occurs.
+func (w *Watcher) Errors() <-chan error {
+	return w.errors
+}
+
+// Close stops the watcher and releases its file handles.
+func (w *Watcher) Close() error {
+	close(w.done)
+	return w.watcher.Close()
+}
+
+// watchFiles (re-)registers the main config file plus everything currently
+// reachable through its Include globs. It is safe to call repeatedly; files
+// already being watched are left alone.
+func (w *Watcher) watchFiles() error {
+	paths := map[string]bool{w.path: true}
+
+	dir := filepath.Dir(w.path)
+	for _, pattern := range w.current.Include {
+		if !filepath.IsAbs(pattern) {
+			pattern = filepath.Join(dir, pattern)
+		}
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return fmt.Errorf("invalid include pattern %q: %w", pattern, err)
+		}
+		for _, match := range matches {
+			paths[match] = true
+		}
+	}
+
+	for p := range paths {
+		if w.watched[p] {
+			continue
+		}
+		if err := w.watcher.Add(p); err != nil {
+			return fmt.Errorf("watching %s: %w", p, err)
+		}
+		w.watched[p] = true
+	}
+
+	return nil
+}
+
+func (w *Watcher) run() {
+	for {
+		select {
+		case <-w.done:
+			return
+		case event, ok := <-w.watcher.Events:
+			if !ok {
+				return
+			}
+			if event.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Remove|fsnotify.Rename) == 0 {
+				continue
+			}
+			// An atomic "write temp file, rename over target" save (common to
+			// editors and config-management tools) replaces the watched path's
+			// inode, which silently invalidates the existing inotify watch.
+			// Forget the path on Remove/Rename so watchFiles re-Adds it
+			// against the new inode instead of leaving it dangling.
+			if event.Op&(fsnotify.Remove|fsnotify.Rename) != 0 {
+				delete(w.watched, event.Name)
+			}
+			w.reload()
+		case err, ok := <-w.watcher.Errors:
+			if !ok {
+				return
+			}
+			w.publishError(fmt.Errorf("file watcher: %w", err))
+		}
+	}
+}
+
+func (w *Watcher) reload() {
+	config, err := LoadConfig(w.path)
+	if err != nil {
+		w.publishError(fmt.Errorf("reloading config: %w", err))
+		return
+	}
+	if err := config.Validate(); err != nil {
+		w.publishError(fmt.Errorf("reloaded config is invalid, keeping previous: %w", err))
+		return
+	}
+
+	w.mu.Lock()
+	w.current = config
+	w.mu.Unlock()
+
+	if err := w.watchFiles(); err != nil {
+		w.publishError(fmt.Errorf("updating watched files: %w", err))
+	}
+
+	select {
+	case w.changes <- config:
+	default:
+		select {
+		case <-w.changes:
+		default:
+		}
+		select {
+		case w.changes <- config:
+		default:
+		}
+		log.Printf("config watcher: dropping stale change notification for %s", w.path)
+	}
+}
+
+func (w *Watcher) publishError(err error) {
+	select {
+	case w.errors <- err:
+	default:
+	}
+}
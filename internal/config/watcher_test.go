@@ -0,0 +1,70 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeConfigJSON(t *testing.T, path, baseURL string) {
+	t.Helper()
+	contents := `{"apis":[{"name":"svc","baseUrl":"` + baseURL + `"}]}`
+	if err := os.WriteFile(path, []byte(contents), 0644); err != nil {
+		t.Fatalf("writing %s: %v", path, err)
+	}
+}
+
+// atomicWrite writes contents to a temp file in dir and renames it over
+// path, the way editors and config-management tools publish a new version
+// without ever exposing a partially-written file.
+func atomicWrite(t *testing.T, path, baseURL string) {
+	t.Helper()
+	dir := filepath.Dir(path)
+	tmp := filepath.Join(dir, ".tmp-config")
+	writeConfigJSON(t, tmp, baseURL)
+	if err := os.Rename(tmp, path); err != nil {
+		t.Fatalf("renaming %s over %s: %v", tmp, path, err)
+	}
+}
+
+func waitForChange(t *testing.T, w *Watcher) *Config {
+	t.Helper()
+	select {
+	case cfg := <-w.Changes():
+		return cfg
+	case err := <-w.Errors():
+		t.Fatalf("watcher reported error: %v", err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for config change")
+	}
+	return nil
+}
+
+// TestWatcherSurvivesRepeatedAtomicRenames reproduces a config being
+// published twice in a row via the "write temp file, rename over target"
+// pattern. Each rename replaces the watched path's inode; the watcher must
+// re-register its watch each time rather than only noticing the first one.
+func TestWatcherSurvivesRepeatedAtomicRenames(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.json")
+	writeConfigJSON(t, path, "http://v1")
+
+	w, err := NewWatcher(path)
+	if err != nil {
+		t.Fatalf("NewWatcher: %v", err)
+	}
+	defer w.Close()
+
+	atomicWrite(t, path, "http://v2")
+	cfg := waitForChange(t, w)
+	if got := cfg.APIs[0].BaseURL; got != "http://v2" {
+		t.Fatalf("after first rename, BaseURL = %q, want %q", got, "http://v2")
+	}
+
+	atomicWrite(t, path, "http://v3")
+	cfg = waitForChange(t, w)
+	if got := cfg.APIs[0].BaseURL; got != "http://v3" {
+		t.Fatalf("after second rename, BaseURL = %q, want %q", got, "http://v3")
+	}
+}
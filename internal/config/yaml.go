@@ -0,0 +1,43 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// isYAMLPath reports whether path's extension indicates a YAML document.
+func isYAMLPath(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return true
+	default:
+		return false
+	}
+}
+
+// loadConfigFile reads and decodes a single config file, auto-detecting
+// JSON vs YAML from its extension. The returned config's raw field holds
+// the exact bytes read, so SaveConfig can round-trip them.
+func loadConfigFile(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading config file %s: %w", path, err)
+	}
+
+	var config Config
+	if isYAMLPath(path) {
+		if err := yaml.Unmarshal(data, &config); err != nil {
+			return nil, fmt.Errorf("error parsing YAML config file %s: %w", path, err)
+		}
+	} else if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("error parsing config file %s: %w", path, err)
+	}
+	config.raw = append([]byte(nil), data...)
+
+	return &config, nil
+}
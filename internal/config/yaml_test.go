@@ -0,0 +1,40 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestIsYAMLPath(t *testing.T) {
+	tests := map[string]bool{
+		"config.yaml": true,
+		"config.YML":  true,
+		"config.json": false,
+		"config":      false,
+	}
+	for path, want := range tests {
+		if got := isYAMLPath(path); got != want {
+			t.Errorf("isYAMLPath(%q) = %v, want %v", path, got, want)
+		}
+	}
+}
+
+func TestLoadConfigFileYAML(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	contents := "server:\n  name: yaml-server\napis:\n  - name: svc\n    baseUrl: http://example.com\n"
+	if err := os.WriteFile(path, []byte(contents), 0644); err != nil {
+		t.Fatalf("writing config: %v", err)
+	}
+
+	cfg, err := loadConfigFile(path)
+	if err != nil {
+		t.Fatalf("loadConfigFile: %v", err)
+	}
+	if got := cfg.Server.Name; got != "yaml-server" {
+		t.Errorf("Server.Name = %q, want %q", got, "yaml-server")
+	}
+	if len(cfg.APIs) != 1 || cfg.APIs[0].BaseURL != "http://example.com" {
+		t.Errorf("APIs = %+v, want a single svc API with baseUrl http://example.com", cfg.APIs)
+	}
+}